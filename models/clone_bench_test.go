@@ -0,0 +1,39 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+)
+
+func makeBenchDomain(n int) *DomainConfig {
+	dc := &DomainConfig{
+		Name:         "example.com",
+		Registrar:    "none",
+		DNSProviders: map[string]int{"r53": 0},
+		Metadata:     map[string]string{"foo": "bar"},
+	}
+	for i := 0; i < n; i++ {
+		dc.Records = append(dc.Records, &RecordConfig{
+			Type:     "A",
+			Name:     fmt.Sprintf("host%d", i),
+			NameFQDN: fmt.Sprintf("host%d.example.com", i),
+			Target:   "1.2.3.4",
+			TTL:      300,
+			Metadata: map[string]string{"k": "v"},
+		})
+	}
+	return dc
+}
+
+// BenchmarkDomainConfigCopy exercises DomainConfig.Copy on a 10k-record zone,
+// the case where the old gob-based copyObj dominated CPU and allocations.
+func BenchmarkDomainConfigCopy(b *testing.B) {
+	dc := makeBenchDomain(10000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := dc.Copy(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
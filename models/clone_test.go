@@ -0,0 +1,64 @@
+package models
+
+import "testing"
+
+// TestRecordConfigCloneNoAliasing checks that mutating a clone's Metadata
+// map doesn't affect the original -- the aliasing bug a field-wise rewrite
+// of a gob-based deep copy is prone to.
+func TestRecordConfigCloneNoAliasing(t *testing.T) {
+	orig := &RecordConfig{
+		Type:       "TXT",
+		Name:       "@",
+		Target:     "hello",
+		Metadata:   map[string]string{"k": "v"},
+		TxtStrings: []string{"a", "b"},
+	}
+
+	clone := orig.Clone()
+	clone.Metadata["k"] = "changed"
+	clone.TxtStrings[0] = "changed"
+
+	if orig.Metadata["k"] != "v" {
+		t.Errorf("mutating clone.Metadata changed orig.Metadata: got %q, want %q", orig.Metadata["k"], "v")
+	}
+	if orig.TxtStrings[0] != "a" {
+		t.Errorf("mutating clone.TxtStrings changed orig.TxtStrings: got %q, want %q", orig.TxtStrings[0], "a")
+	}
+}
+
+// TestDomainConfigCloneNoAliasing checks the same for DomainConfig's
+// DNSProviders/Metadata maps and its Records/Nameservers slices.
+func TestDomainConfigCloneNoAliasing(t *testing.T) {
+	orig := &DomainConfig{
+		Name:         "example.com",
+		DNSProviders: map[string]int{"r53": 0},
+		Metadata:     map[string]string{"k": "v"},
+		Records: Records{
+			&RecordConfig{Type: "A", Name: "@", Target: "1.2.3.4", Metadata: map[string]string{"a": "1"}},
+		},
+		Nameservers: []*Nameserver{{Name: "ns1.example.com"}},
+	}
+
+	clone := orig.Clone()
+	clone.DNSProviders["r53"] = 99
+	clone.Metadata["k"] = "changed"
+	clone.Records[0].Target = "5.6.7.8"
+	clone.Records[0].Metadata["a"] = "changed"
+	clone.Nameservers[0].Name = "ns2.example.com"
+
+	if orig.DNSProviders["r53"] != 0 {
+		t.Errorf("mutating clone.DNSProviders changed orig: got %d, want %d", orig.DNSProviders["r53"], 0)
+	}
+	if orig.Metadata["k"] != "v" {
+		t.Errorf("mutating clone.Metadata changed orig.Metadata: got %q, want %q", orig.Metadata["k"], "v")
+	}
+	if orig.Records[0].Target != "1.2.3.4" {
+		t.Errorf("mutating clone.Records[0] changed orig.Records[0].Target: got %q, want %q", orig.Records[0].Target, "1.2.3.4")
+	}
+	if orig.Records[0].Metadata["a"] != "1" {
+		t.Errorf("mutating clone.Records[0].Metadata changed orig: got %q, want %q", orig.Records[0].Metadata["a"], "1")
+	}
+	if orig.Nameservers[0].Name != "ns1.example.com" {
+		t.Errorf("mutating clone.Nameservers[0] changed orig.Nameservers[0].Name: got %q, want %q", orig.Nameservers[0].Name, "ns1.example.com")
+	}
+}
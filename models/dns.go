@@ -1,8 +1,6 @@
 package models
 
 import (
-	"bytes"
-	"encoding/gob"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -14,6 +12,7 @@ import (
 	"github.com/StackExchange/dnscontrol/transform"
 	"github.com/miekg/dns"
 	"github.com/miekg/dns/dnsutil"
+	"golang.org/x/net/idna"
 )
 
 const DefaultTTL = uint32(300)
@@ -33,6 +32,107 @@ func (config *DNSConfig) FindDomain(query string) *DomainConfig {
 	return nil
 }
 
+// NormalizeIDN walks every domain and record, converting any non-ASCII
+// labels in DomainConfig.Name, RecordConfig.Name/NameFQDN and CNAME/MX/NS/SRV
+// targets into punycode (A-labels) via idna.Lookup.ToASCII. The original
+// U-label is retained in Metadata["original_name"] so correction messages
+// can still show the human-readable form. It is meant to be run as part of
+// DNSConfig validation, after all domains/records have been parsed from the
+// JS DSL.
+func (config *DNSConfig) NormalizeIDN() error {
+	for _, dc := range config.Domains {
+		asciiName, err := idnaToASCII(dc.Name)
+		if err != nil {
+			return fmt.Errorf("domain %#v is not valid IDNA2008: %v", dc.Name, err)
+		}
+		if asciiName != dc.Name {
+			if dc.Metadata == nil {
+				dc.Metadata = map[string]string{}
+			}
+			dc.Metadata["original_name"] = dc.Name
+			dc.Name = asciiName
+		}
+		for _, rc := range dc.Records {
+			if err := rc.normalizeIDN(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// idnTargetTypes are the record types whose Target is itself a domain name
+// and therefore subject to IDN normalization.
+var idnTargetTypes = map[string]bool{
+	"CNAME": true,
+	"MX":    true,
+	"NS":    true,
+	"SRV":   true,
+}
+
+func (r *RecordConfig) normalizeIDN() error {
+	origName := r.Name
+
+	asciiName, err := idnaToASCII(r.Name)
+	if err != nil {
+		return fmt.Errorf("record name %#v is not valid IDNA2008: %v", r.Name, err)
+	}
+	asciiFQDN, err := idnaToASCII(r.NameFQDN)
+	if err != nil {
+		return fmt.Errorf("record name %#v is not valid IDNA2008: %v", r.NameFQDN, err)
+	}
+
+	if idnTargetTypes[r.Type] {
+		asciiTarget, err := idnaToASCII(r.Target)
+		if err != nil {
+			return fmt.Errorf("target %#v of %s record %#v is not valid IDNA2008: %v", r.Target, r.Type, r.NameFQDN, err)
+		}
+		r.Target = asciiTarget
+	}
+
+	if asciiName != r.Name || asciiFQDN != r.NameFQDN {
+		if r.Metadata == nil {
+			r.Metadata = map[string]string{}
+		}
+		r.Metadata["original_name"] = origName
+		r.Name = asciiName
+		r.NameFQDN = asciiFQDN
+	}
+	return nil
+}
+
+// idnaToASCII converts s to its punycode (A-label) form, one label at a
+// time. The idna.Lookup profile enforces StrictDomainName/ValidateLabels,
+// which rejects "@", "*" and "_"-prefixed labels that are perfectly legal
+// DNS record names (apex records, wildcards, DKIM/SPF/DMARC/ACME/SRV); running
+// it over the whole dotted name would reject those the moment any other
+// label in the name is non-ASCII, so those labels are passed through
+// unconverted and only the remaining labels go through idna.Lookup.ToASCII.
+func idnaToASCII(s string) (string, error) {
+	labels := strings.Split(s, ".")
+	for i, label := range labels {
+		if label == "@" || label == "*" || strings.HasPrefix(label, "_") {
+			continue
+		}
+		ascii, err := idna.Lookup.ToASCII(label)
+		if err != nil {
+			return "", fmt.Errorf("label %#v of %#v is not valid IDNA2008: %v", label, s, err)
+		}
+		labels[i] = ascii
+	}
+	return strings.Join(labels, "."), nil
+}
+
+// NameUnicode returns the human-readable (U-label) form of the record's
+// name, as it was originally written in the JS DSL, or Name unchanged if it
+// was pure ASCII to begin with.
+func (r *RecordConfig) NameUnicode() string {
+	if orig, ok := r.Metadata["original_name"]; ok {
+		return orig
+	}
+	return r.Name
+}
+
 type RegistrarConfig struct {
 	Name     string          `json:"name"`
 	Type     string          `json:"type"`
@@ -69,9 +169,102 @@ type RecordConfig struct {
 	NameFQDN string            `json:"-"` // Must end with ".$origin". See below.
 	Priority uint16            `json:"priority,omitempty"`
 
+	// TxtStrings holds the original chunking of a multi-string TXT record.
+	// RRToRecord/RR() keep Target in sync as strings.Join(TxtStrings, " ")
+	// when going through a dns.RR, but nothing enforces that invariant on a
+	// RecordConfig built by hand: if you set TxtStrings directly, also set
+	// Target if any caller of yours reads it for display. Anything that
+	// needs the exact 255-byte chunks (DKIM, SPF, ACME) must use TxtStrings.
+	TxtStrings []string `json:"txtstrings,omitempty"`
+
+	// SoaFields holds the structured fields of an SOA record. For SOA
+	// records, Target is unused; the fields here are diffed individually so
+	// that, for example, a serial bump doesn't look like a full replacement.
+	SoaFields SoaFields `json:"soa,omitempty"`
+
+	// SRV fields. Weight/Port are SRV-only; Priority (above) is shared with MX.
+	SrvWeight uint16 `json:"srvweight,omitempty"`
+	SrvPort   uint16 `json:"srvport,omitempty"`
+
+	// CAA fields.
+	CaaTag  string `json:"caatag,omitempty"`
+	CaaFlag uint8  `json:"caaflag,omitempty"`
+
+	// NAPTR fields. Target holds the replacement.
+	NaptrOrder      uint16 `json:"naptrorder,omitempty"`
+	NaptrPreference uint16 `json:"naptrpreference,omitempty"`
+	NaptrFlags      string `json:"naptrflags,omitempty"`
+	NaptrService    string `json:"naptrservice,omitempty"`
+	NaptrRegexp     string `json:"naptrregexp,omitempty"`
+
+	// SSHFP fields. Target holds the fingerprint (hex).
+	SshfpAlgorithm       uint8 `json:"sshfpalgorithm,omitempty"`
+	SshfpFingerprintType uint8 `json:"sshfpfingerprinttype,omitempty"`
+
+	// TLSA fields. Target holds the certificate association data (hex).
+	TlsaUsage        uint8 `json:"tlsausage,omitempty"`
+	TlsaSelector     uint8 `json:"tlsaselector,omitempty"`
+	TlsaMatchingType uint8 `json:"tlsamatchingtype,omitempty"`
+
+	// DS fields. Target holds the digest (hex).
+	DsKeyTag     uint16 `json:"dskeytag,omitempty"`
+	DsAlgorithm  uint8  `json:"dsalgorithm,omitempty"`
+	DsDigestType uint8  `json:"dsdigesttype,omitempty"`
+
+	// DNSKEY fields. Target holds the public key (base64).
+	DnskeyFlags     uint16 `json:"dnskeyflags,omitempty"`
+	DnskeyProtocol  uint8  `json:"dnskeyprotocol,omitempty"`
+	DnskeyAlgorithm uint8  `json:"dnskeyalgorithm,omitempty"`
+
+	// CERT fields. Target holds the certificate (base64).
+	CertType      uint16 `json:"certtype,omitempty"`
+	CertKeyTag    uint16 `json:"certkeytag,omitempty"`
+	CertAlgorithm uint8  `json:"certalgorithm,omitempty"`
+
 	Original interface{} `json:"-"` // Store pointer to provider-specific record object. Used in diffing.
 }
 
+// SoaFields holds the structured fields of an SOA record, parsed in
+// RRToRecord and consumed by RR(). Keeping these separate from a joined
+// Target string lets the diff engine distinguish a serial bump from a real
+// content change, and lets providers that auto-manage the serial ignore
+// just that field.
+type SoaFields struct {
+	Ns      string
+	Mbox    string
+	Serial  uint32
+	Refresh uint32
+	Retry   uint32
+	Expire  uint32
+	Minttl  uint32
+}
+
+// Capability identifies an optional DNS record type or feature that a
+// provider may or may not be able to represent. Providers should check
+// these before accepting a RecordConfig so that unsupported types are
+// rejected with a clear error instead of being silently degraded to a
+// plain string Target.
+//
+// NOTE: this tree has no providers package to wire these into yet (see
+// the other structured-record commits in this series), so nothing in
+// this repo snapshot actually checks them. They're defined here so a
+// provider's rejection logic has a shared vocabulary to check against
+// once one exists.
+type Capability string
+
+// Capabilities for the structured record types added to RecordConfig.
+const (
+	CanUseSRV    Capability = "CAN_USE_SRV"
+	CanUseCAA    Capability = "CAN_USE_CAA"
+	CanUsePTR    Capability = "CAN_USE_PTR"
+	CanUseNAPTR  Capability = "CAN_USE_NAPTR"
+	CanUseSSHFP  Capability = "CAN_USE_SSHFP"
+	CanUseTLSA   Capability = "CAN_USE_TLSA"
+	CanUseDS     Capability = "CAN_USE_DS"
+	CanUseDNSKEY Capability = "CAN_USE_DNSKEY"
+	CanUseCERT   Capability = "CAN_USE_CERT"
+)
+
 func (r *RecordConfig) String() string {
 	if r == nil {
 		return "?"
@@ -80,6 +273,10 @@ func (r *RecordConfig) String() string {
 	if r.Type == "MX" {
 		content += fmt.Sprintf(" priority=%d", r.Priority)
 	}
+	if r.Type == "SOA" {
+		content += fmt.Sprintf(" ns=%s mbox=%s serial=%d refresh=%d retry=%d expire=%d minttl=%d",
+			r.SoaFields.Ns, r.SoaFields.Mbox, r.SoaFields.Serial, r.SoaFields.Refresh, r.SoaFields.Retry, r.SoaFields.Expire, r.SoaFields.Minttl)
+	}
 	for k, v := range r.Metadata {
 		content += fmt.Sprintf(" %s=%s", k, v)
 	}
@@ -114,7 +311,71 @@ func (r *RecordConfig) RR() dns.RR {
 		return &dns.MX{Hdr: hdr, Preference: r.Priority, Mx: r.Target}
 	case dns.TypeTXT:
 		// Assure no problems due to quoting/unquoting:
+		if len(r.TxtStrings) > 0 {
+			return &dns.TXT{Hdr: hdr, Txt: r.TxtStrings}
+		}
 		return &dns.TXT{Hdr: hdr, Txt: []string{r.Target}}
+	case dns.TypeSRV:
+		return &dns.SRV{Hdr: hdr, Priority: r.Priority, Weight: r.SrvWeight, Port: r.SrvPort, Target: r.Target}
+	case dns.TypeCAA:
+		return &dns.CAA{Hdr: hdr, Flag: r.CaaFlag, Tag: r.CaaTag, Value: r.Target}
+	case dns.TypePTR:
+		return &dns.PTR{Hdr: hdr, Ptr: r.Target}
+	case dns.TypeNAPTR:
+		return &dns.NAPTR{
+			Hdr:         hdr,
+			Order:       r.NaptrOrder,
+			Preference:  r.NaptrPreference,
+			Flags:       r.NaptrFlags,
+			Service:     r.NaptrService,
+			Regexp:      r.NaptrRegexp,
+			Replacement: r.Target,
+		}
+	case dns.TypeSSHFP:
+		return &dns.SSHFP{Hdr: hdr, Algorithm: r.SshfpAlgorithm, Type: r.SshfpFingerprintType, FingerPrint: r.Target}
+	case dns.TypeTLSA:
+		return &dns.TLSA{
+			Hdr:          hdr,
+			Usage:        r.TlsaUsage,
+			Selector:     r.TlsaSelector,
+			MatchingType: r.TlsaMatchingType,
+			Certificate:  r.Target,
+		}
+	case dns.TypeDS:
+		return &dns.DS{
+			Hdr:        hdr,
+			KeyTag:     r.DsKeyTag,
+			Algorithm:  r.DsAlgorithm,
+			DigestType: r.DsDigestType,
+			Digest:     r.Target,
+		}
+	case dns.TypeDNSKEY:
+		return &dns.DNSKEY{
+			Hdr:       hdr,
+			Flags:     r.DnskeyFlags,
+			Protocol:  r.DnskeyProtocol,
+			Algorithm: r.DnskeyAlgorithm,
+			PublicKey: r.Target,
+		}
+	case dns.TypeCERT:
+		return &dns.CERT{
+			Hdr:         hdr,
+			Type:        r.CertType,
+			KeyTag:      r.CertKeyTag,
+			Algorithm:   r.CertAlgorithm,
+			Certificate: r.Target,
+		}
+	case dns.TypeSOA:
+		return &dns.SOA{
+			Hdr:     hdr,
+			Ns:      r.SoaFields.Ns,
+			Mbox:    r.SoaFields.Mbox,
+			Serial:  r.SoaFields.Serial,
+			Refresh: r.SoaFields.Refresh,
+			Retry:   r.SoaFields.Retry,
+			Expire:  r.SoaFields.Expire,
+			Minttl:  r.SoaFields.Minttl,
+		}
 	default:
 	}
 
@@ -155,16 +416,89 @@ func RRToRecord(rr dns.RR, origin string) (*RecordConfig, error) {
 	case *dns.NS:
 		rc.Target = v.Ns
 	case *dns.SOA:
-		rc.Target = fmt.Sprintf("%v %v %v %v %v %v %v",
-			v.Ns, v.Mbox, v.Serial, v.Refresh, v.Retry, v.Expire, v.Minttl)
+		rc.SoaFields = SoaFields{
+			Ns:      v.Ns,
+			Mbox:    v.Mbox,
+			Serial:  v.Serial,
+			Refresh: v.Refresh,
+			Retry:   v.Retry,
+			Expire:  v.Expire,
+			Minttl:  v.Minttl,
+		}
 	case *dns.TXT:
 		rc.Target = strings.Join(v.Txt, " ")
+		rc.TxtStrings = v.Txt
+	case *dns.SRV:
+		rc.Target = v.Target
+		rc.Priority = v.Priority
+		rc.SrvWeight = v.Weight
+		rc.SrvPort = v.Port
+	case *dns.CAA:
+		rc.Target = v.Value
+		rc.CaaTag = v.Tag
+		rc.CaaFlag = v.Flag
+	case *dns.PTR:
+		rc.Target = v.Ptr
+	case *dns.NAPTR:
+		rc.Target = v.Replacement
+		rc.NaptrOrder = v.Order
+		rc.NaptrPreference = v.Preference
+		rc.NaptrFlags = v.Flags
+		rc.NaptrService = v.Service
+		rc.NaptrRegexp = v.Regexp
+	case *dns.SSHFP:
+		rc.Target = v.FingerPrint
+		rc.SshfpAlgorithm = v.Algorithm
+		rc.SshfpFingerprintType = v.Type
+	case *dns.TLSA:
+		rc.Target = v.Certificate
+		rc.TlsaUsage = v.Usage
+		rc.TlsaSelector = v.Selector
+		rc.TlsaMatchingType = v.MatchingType
+	case *dns.DS:
+		rc.Target = v.Digest
+		rc.DsKeyTag = v.KeyTag
+		rc.DsAlgorithm = v.Algorithm
+		rc.DsDigestType = v.DigestType
+	case *dns.DNSKEY:
+		rc.Target = v.PublicKey
+		rc.DnskeyFlags = v.Flags
+		rc.DnskeyProtocol = v.Protocol
+		rc.DnskeyAlgorithm = v.Algorithm
+	case *dns.CERT:
+		rc.Target = v.Certificate
+		rc.CertType = v.Type
+		rc.CertKeyTag = v.KeyTag
+		rc.CertAlgorithm = v.Algorithm
 	default:
 		return nil, fmt.Errorf("unimplemented zone record type=%s (%v)", rc.Type, rr)
 	}
 	return rc, nil
 }
 
+// RRsToRecords converts a list of dns.RR into Records, tagging each with origin.
+func RRsToRecords(rrs []dns.RR, origin string) (Records, error) {
+	recs := make(Records, 0, len(rrs))
+	for _, rr := range rrs {
+		rc, err := RRToRecord(rr, origin)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, rc)
+	}
+	return recs, nil
+}
+
+// ToRRs converts Records back into a list of dns.RR, suitable for AXFR-style
+// upload or writing out a zone file.
+func (r Records) ToRRs() []dns.RR {
+	rrs := make([]dns.RR, 0, len(r))
+	for _, rc := range r {
+		rrs = append(rrs, rc.RR())
+	}
+	return rrs
+}
+
 type Nameserver struct {
 	Name   string `json:"name"` // Normalized to a FQDN with NO trailing "."
 	Target string `json:"target"`
@@ -180,6 +514,12 @@ func StringsToNameservers(nss []string) []*Nameserver {
 	return nservers
 }
 
+// Clone returns a deep copy of the Nameserver.
+func (ns *Nameserver) Clone() *Nameserver {
+	newNs := *ns
+	return &newNs
+}
+
 type Records []*RecordConfig
 
 type RecordKey struct {
@@ -196,6 +536,18 @@ func (r Records) Grouped() map[RecordKey]Records {
 	return m
 }
 
+// Clone returns a deep copy of Records.
+func (r Records) Clone() Records {
+	if r == nil {
+		return nil
+	}
+	newR := make(Records, len(r))
+	for i, rc := range r {
+		newR[i] = rc.Clone()
+	}
+	return newR
+}
+
 type DomainConfig struct {
 	Name         string            `json:"name"` // NO trailing "."
 	Registrar    string            `json:"registrar"`
@@ -206,29 +558,57 @@ type DomainConfig struct {
 	KeepUnknown  bool              `json:"keepunknown"`
 }
 
-func (dc *DomainConfig) Copy() (*DomainConfig, error) {
-	newDc := &DomainConfig{}
-	err := copyObj(dc, newDc)
-	return newDc, err
+// Clone returns a deep copy of the DomainConfig.
+func (dc *DomainConfig) Clone() *DomainConfig {
+	newDc := *dc
+	if dc.DNSProviders != nil {
+		newDc.DNSProviders = make(map[string]int, len(dc.DNSProviders))
+		for k, v := range dc.DNSProviders {
+			newDc.DNSProviders[k] = v
+		}
+	}
+	if dc.Metadata != nil {
+		newDc.Metadata = make(map[string]string, len(dc.Metadata))
+		for k, v := range dc.Metadata {
+			newDc.Metadata[k] = v
+		}
+	}
+	newDc.Records = dc.Records.Clone()
+	if dc.Nameservers != nil {
+		newDc.Nameservers = make([]*Nameserver, len(dc.Nameservers))
+		for i, ns := range dc.Nameservers {
+			newDc.Nameservers[i] = ns.Clone()
+		}
+	}
+	return &newDc
 }
 
-func (r *RecordConfig) Copy() (*RecordConfig, error) {
-	newR := &RecordConfig{}
-	err := copyObj(r, newR)
-	return newR, err
+// Copy returns a deep copy of the DomainConfig. It never returns an error;
+// the error return is kept for compatibility with existing callers.
+func (dc *DomainConfig) Copy() (*DomainConfig, error) {
+	return dc.Clone(), nil
 }
 
-func copyObj(input interface{}, output interface{}) error {
-	buf := &bytes.Buffer{}
-	enc := gob.NewEncoder(buf)
-	dec := gob.NewDecoder(buf)
-	if err := enc.Encode(input); err != nil {
-		return err
+// Clone returns a deep copy of the RecordConfig. The provider-specific
+// Original value is not copied; the clone points at the same object.
+func (r *RecordConfig) Clone() *RecordConfig {
+	newR := *r
+	if r.Metadata != nil {
+		newR.Metadata = make(map[string]string, len(r.Metadata))
+		for k, v := range r.Metadata {
+			newR.Metadata[k] = v
+		}
 	}
-	if err := dec.Decode(output); err != nil {
-		return err
+	if r.TxtStrings != nil {
+		newR.TxtStrings = append([]string(nil), r.TxtStrings...)
 	}
-	return nil
+	return &newR
+}
+
+// Copy returns a deep copy of the RecordConfig. It never returns an error;
+// the error return is kept for compatibility with existing callers.
+func (r *RecordConfig) Copy() (*RecordConfig, error) {
+	return r.Clone(), nil
 }
 
 func (dc *DomainConfig) HasRecordTypeName(rtype, name string) bool {
@@ -0,0 +1,71 @@
+package models
+
+import "testing"
+
+// TestNormalizeIDNAllowsLegalDNSLabels ensures ordinary ASCII DNS labels
+// that are not valid IDNA domain labels (apex "@", wildcard "*", and
+// "_"-prefixed labels used by DKIM/SPF/DMARC/ACME/SRV) pass through
+// NormalizeIDN untouched instead of being rejected.
+func TestNormalizeIDNAllowsLegalDNSLabels(t *testing.T) {
+	cases := []struct {
+		rType, name, fqdn, target string
+	}{
+		{"NS", "@", "example.com", "ns1.example.com"},
+		{"A", "*", "*.example.com", ""},
+		{"TXT", "_dmarc", "_dmarc.example.com", ""},
+		{"SRV", "_sip._tcp", "_sip._tcp.example.com", "sipserver.example.com"},
+	}
+
+	for _, c := range cases {
+		rc := &RecordConfig{Type: c.rType, Name: c.name, NameFQDN: c.fqdn, Target: c.target}
+		if err := rc.normalizeIDN(); err != nil {
+			t.Errorf("normalizeIDN() on %s record %q: unexpected error: %v", c.rType, c.name, err)
+		}
+		if rc.Name != c.name {
+			t.Errorf("normalizeIDN() changed Name %q to %q", c.name, rc.Name)
+		}
+	}
+}
+
+// TestNormalizeIDNConvertsUnicode ensures genuine unicode labels are still
+// converted to punycode with the original retained for display.
+func TestNormalizeIDNConvertsUnicode(t *testing.T) {
+	rc := &RecordConfig{Type: "A", Name: "café", NameFQDN: "café.example.com", Target: "1.2.3.4"}
+	if err := rc.normalizeIDN(); err != nil {
+		t.Fatalf("normalizeIDN() unexpected error: %v", err)
+	}
+	if rc.Name == "café" {
+		t.Errorf("expected unicode Name to be converted to punycode, got %q", rc.Name)
+	}
+	if rc.NameUnicode() != "café" {
+		t.Errorf("NameUnicode() = %q, want %q", rc.NameUnicode(), "café")
+	}
+}
+
+// TestNormalizeIDNAllowsLegalLabelsOnUnicodeDomain ensures "@", "*" and
+// "_"-prefixed labels are still accepted when the rest of the domain is
+// unicode, instead of the whole dotted name being rejected because one
+// label fails StrictDomainName validation.
+func TestNormalizeIDNAllowsLegalLabelsOnUnicodeDomain(t *testing.T) {
+	cases := []struct {
+		rType, name, fqdn string
+	}{
+		{"TXT", "_dmarc", "_dmarc.münchen.de"},
+		{"A", "*", "*.münchen.de"},
+		{"SRV", "_sip._tcp", "_sip._tcp.münchen.de"},
+	}
+
+	for _, c := range cases {
+		rc := &RecordConfig{Type: c.rType, Name: c.name, NameFQDN: c.fqdn}
+		if err := rc.normalizeIDN(); err != nil {
+			t.Errorf("normalizeIDN() on %s record %q: unexpected error: %v", c.rType, c.fqdn, err)
+			continue
+		}
+		if rc.Name != c.name {
+			t.Errorf("normalizeIDN() changed Name %q to %q", c.name, rc.Name)
+		}
+		if rc.NameFQDN == c.fqdn {
+			t.Errorf("normalizeIDN() left NameFQDN %q unconverted", c.fqdn)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package models
+
+import "testing"
+
+// TestStructuredRecordRoundTrip converts a RecordConfig to a dns.RR via RR()
+// and back via RRToRecord for each of the structured record types added in
+// this series, checking that the type-specific fields survive the
+// round-trip. This is the kind of test that would have caught the SSHFP
+// fingerprint-type/Target mixup.
+func TestStructuredRecordRoundTrip(t *testing.T) {
+	origin := "example.com"
+	tests := []struct {
+		name string
+		rc   *RecordConfig
+		// check compares the fields that are specific to this record type.
+		check func(t *testing.T, got, want *RecordConfig)
+	}{
+		{
+			name: "SRV",
+			rc: &RecordConfig{
+				Type: "SRV", Name: "_sip._tcp", NameFQDN: "_sip._tcp.example.com",
+				Target: "sipserver.example.com.", TTL: 300,
+				Priority: 10, SrvWeight: 20, SrvPort: 5060,
+			},
+			check: func(t *testing.T, got, want *RecordConfig) {
+				if got.Priority != want.Priority || got.SrvWeight != want.SrvWeight || got.SrvPort != want.SrvPort {
+					t.Errorf("SRV fields = %+v, want %+v", got, want)
+				}
+			},
+		},
+		{
+			name: "CAA",
+			rc: &RecordConfig{
+				Type: "CAA", Name: "@", NameFQDN: "example.com",
+				Target: "letsencrypt.org", TTL: 300,
+				CaaFlag: 128, CaaTag: "issue",
+			},
+			check: func(t *testing.T, got, want *RecordConfig) {
+				if got.CaaFlag != want.CaaFlag || got.CaaTag != want.CaaTag {
+					t.Errorf("CAA fields = %+v, want %+v", got, want)
+				}
+			},
+		},
+		{
+			name: "PTR",
+			rc: &RecordConfig{
+				Type: "PTR", Name: "1", NameFQDN: "1.0.0.127.in-addr.arpa",
+				Target: "host.example.com.", TTL: 300,
+			},
+			check: func(t *testing.T, got, want *RecordConfig) {},
+		},
+		{
+			name: "NAPTR",
+			rc: &RecordConfig{
+				Type: "NAPTR", Name: "@", NameFQDN: "example.com",
+				Target: "replacement.example.com.", TTL: 300,
+				NaptrOrder: 100, NaptrPreference: 10, NaptrFlags: "u",
+				NaptrService: "e2u+sip", NaptrRegexp: "!^.*$!sip:info@example.com!",
+			},
+			check: func(t *testing.T, got, want *RecordConfig) {
+				if got.NaptrOrder != want.NaptrOrder || got.NaptrPreference != want.NaptrPreference ||
+					got.NaptrFlags != want.NaptrFlags || got.NaptrService != want.NaptrService ||
+					got.NaptrRegexp != want.NaptrRegexp {
+					t.Errorf("NAPTR fields = %+v, want %+v", got, want)
+				}
+			},
+		},
+		{
+			name: "SSHFP",
+			rc: &RecordConfig{
+				Type: "SSHFP", Name: "@", NameFQDN: "example.com",
+				Target: "123456789abcdef67890123456789abcdef67890", TTL: 300,
+				SshfpAlgorithm: 1, SshfpFingerprintType: 2,
+			},
+			check: func(t *testing.T, got, want *RecordConfig) {
+				if got.SshfpAlgorithm != want.SshfpAlgorithm || got.SshfpFingerprintType != want.SshfpFingerprintType {
+					t.Errorf("SSHFP fields = %+v, want %+v", got, want)
+				}
+			},
+		},
+		{
+			name: "TLSA",
+			rc: &RecordConfig{
+				Type: "TLSA", Name: "_443._tcp", NameFQDN: "_443._tcp.example.com",
+				Target: "abcdef0123456789", TTL: 300,
+				TlsaUsage: 3, TlsaSelector: 1, TlsaMatchingType: 1,
+			},
+			check: func(t *testing.T, got, want *RecordConfig) {
+				if got.TlsaUsage != want.TlsaUsage || got.TlsaSelector != want.TlsaSelector || got.TlsaMatchingType != want.TlsaMatchingType {
+					t.Errorf("TLSA fields = %+v, want %+v", got, want)
+				}
+			},
+		},
+		{
+			name: "DS",
+			rc: &RecordConfig{
+				Type: "DS", Name: "@", NameFQDN: "example.com",
+				Target: "abcdef0123456789abcdef0123456789abcdef01", TTL: 300,
+				DsKeyTag: 12345, DsAlgorithm: 8, DsDigestType: 2,
+			},
+			check: func(t *testing.T, got, want *RecordConfig) {
+				if got.DsKeyTag != want.DsKeyTag || got.DsAlgorithm != want.DsAlgorithm || got.DsDigestType != want.DsDigestType {
+					t.Errorf("DS fields = %+v, want %+v", got, want)
+				}
+			},
+		},
+		{
+			name: "DNSKEY",
+			rc: &RecordConfig{
+				Type: "DNSKEY", Name: "@", NameFQDN: "example.com",
+				Target: "AwEAAagSomeBase64Key==", TTL: 300,
+				DnskeyFlags: 257, DnskeyProtocol: 3, DnskeyAlgorithm: 8,
+			},
+			check: func(t *testing.T, got, want *RecordConfig) {
+				if got.DnskeyFlags != want.DnskeyFlags || got.DnskeyProtocol != want.DnskeyProtocol || got.DnskeyAlgorithm != want.DnskeyAlgorithm {
+					t.Errorf("DNSKEY fields = %+v, want %+v", got, want)
+				}
+			},
+		},
+		{
+			name: "CERT",
+			rc: &RecordConfig{
+				Type: "CERT", Name: "@", NameFQDN: "example.com",
+				Target: "SomeBase64Cert==", TTL: 300,
+				CertType: 1, CertKeyTag: 12345, CertAlgorithm: 8,
+			},
+			check: func(t *testing.T, got, want *RecordConfig) {
+				if got.CertType != want.CertType || got.CertKeyTag != want.CertKeyTag || got.CertAlgorithm != want.CertAlgorithm {
+					t.Errorf("CERT fields = %+v, want %+v", got, want)
+				}
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := tc.rc.RR()
+			got, err := RRToRecord(rr, origin)
+			if err != nil {
+				t.Fatalf("RRToRecord() error = %v", err)
+			}
+			if got.Target != tc.rc.Target {
+				t.Errorf("Target = %q, want %q", got.Target, tc.rc.Target)
+			}
+			tc.check(t, got, tc.rc)
+		})
+	}
+}
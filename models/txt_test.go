@@ -0,0 +1,55 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestTxtStringsRoundTrip checks that a multi-chunk TXT record keeps its
+// original chunking through RR() -> RRToRecord(), which matters for DKIM,
+// SPF and ACME challenges that split a value across multiple 255-byte
+// strings.
+func TestTxtStringsRoundTrip(t *testing.T) {
+	rc := &RecordConfig{
+		Type:       "TXT",
+		Name:       "_dmarc",
+		NameFQDN:   "_dmarc.example.com",
+		TTL:        300,
+		TxtStrings: []string{"v=DKIM1; k=rsa; p=AAAA", "BBBB", "CCCC"},
+	}
+
+	rr := rc.RR()
+	got, err := RRToRecord(rr, "example.com")
+	if err != nil {
+		t.Fatalf("RRToRecord() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got.TxtStrings, rc.TxtStrings) {
+		t.Errorf("TxtStrings = %#v, want %#v", got.TxtStrings, rc.TxtStrings)
+	}
+}
+
+// TestTxtTargetFallback checks that a TXT record with no TxtStrings set
+// (the plain single-string case) still round-trips via Target.
+func TestTxtTargetFallback(t *testing.T) {
+	rc := &RecordConfig{
+		Type:     "TXT",
+		Name:     "@",
+		NameFQDN: "example.com",
+		TTL:      300,
+		Target:   "v=spf1 -all",
+	}
+
+	rr := rc.RR()
+	got, err := RRToRecord(rr, "example.com")
+	if err != nil {
+		t.Fatalf("RRToRecord() error = %v", err)
+	}
+
+	if got.Target != rc.Target {
+		t.Errorf("Target = %q, want %q", got.Target, rc.Target)
+	}
+	if !reflect.DeepEqual(got.TxtStrings, []string{rc.Target}) {
+		t.Errorf("TxtStrings = %#v, want %#v", got.TxtStrings, []string{rc.Target})
+	}
+}
@@ -0,0 +1,73 @@
+// Package zonefile reads and writes BIND-style zone files, letting users
+// mass-import existing zones into dnscontrol and use dnscontrol as a
+// zone-file generator for providers that accept AXFR-style uploads.
+package zonefile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/StackExchange/dnscontrol/models"
+	"github.com/miekg/dns"
+)
+
+// Read parses a BIND-format zone file for origin and returns a DomainConfig
+// populated with its records.
+func Read(r io.Reader, origin string) (*models.DomainConfig, error) {
+	zp := dns.NewZoneParser(r, origin, "")
+
+	var rrs []dns.RR
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		rrs = append(rrs, rr)
+	}
+	if err := zp.Err(); err != nil {
+		return nil, fmt.Errorf("error parsing zone file for %s: %v", origin, err)
+	}
+
+	recs, err := models.RRsToRecords(rrs, origin)
+	if err != nil {
+		return nil, err
+	}
+
+	dc := &models.DomainConfig{
+		Name:    strings.TrimSuffix(origin, "."),
+		Records: recs,
+	}
+	return dc, nil
+}
+
+// Write serializes dc as a BIND-format zone file, grouping records by name
+// and type with a stable ordering, and writes it to w.
+func Write(w io.Writer, dc *models.DomainConfig) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "$ORIGIN %s.\n", dc.Name)
+	fmt.Fprintf(bw, "$TTL %d\n", models.DefaultTTL)
+
+	grouped := dc.Records.Grouped()
+	keys := make([]models.RecordKey, 0, len(grouped))
+	for k := range grouped {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Name != keys[j].Name {
+			return keys[i].Name < keys[j].Name
+		}
+		return keys[i].Type < keys[j].Type
+	})
+
+	for _, key := range keys {
+		recs := grouped[key]
+		sort.SliceStable(recs, func(i, j int) bool {
+			return recs[i].Target < recs[j].Target
+		})
+		for _, rc := range recs {
+			fmt.Fprintf(bw, "%s\n", rc.RR().String())
+		}
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,59 @@
+package zonefile
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/models"
+)
+
+// TestReadWriteRoundTrip writes a DomainConfig out as a zone file and reads
+// it back, checking that every record's type and content survive.
+func TestReadWriteRoundTrip(t *testing.T) {
+	origin := "example.com"
+	dc := &models.DomainConfig{
+		Name: origin,
+		Records: models.Records{
+			&models.RecordConfig{Type: "A", Name: "www", NameFQDN: "www.example.com", Target: "1.2.3.4", TTL: 300},
+			&models.RecordConfig{Type: "MX", Name: "@", NameFQDN: "example.com", Target: "mail.example.com.", TTL: 300, Priority: 10},
+			&models.RecordConfig{Type: "TXT", Name: "@", NameFQDN: "example.com", TTL: 300, TxtStrings: []string{"v=spf1 -all"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, dc); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	got, err := Read(&buf, origin+".")
+	if err != nil {
+		t.Fatalf("Read() error = %v, zone file was:\n%s", err, buf.String())
+	}
+
+	if len(got.Records) != len(dc.Records) {
+		t.Fatalf("Read() got %d records, want %d; zone file was:\n%s", len(got.Records), len(dc.Records), buf.String())
+	}
+
+	wantTargets := map[string]string{}
+	for _, rc := range dc.Records {
+		wantTargets[rc.Type] = rc.Target
+	}
+	for _, rc := range got.Records {
+		switch rc.Type {
+		case "A":
+			if rc.Target != wantTargets["A"] {
+				t.Errorf("A target = %q, want %q", rc.Target, wantTargets["A"])
+			}
+		case "MX":
+			if rc.Target != wantTargets["MX"] || rc.Priority != 10 {
+				t.Errorf("MX = %+v, want target %q priority 10", rc, wantTargets["MX"])
+			}
+		case "TXT":
+			if len(rc.TxtStrings) != 1 || rc.TxtStrings[0] != "v=spf1 -all" {
+				t.Errorf("TXT TxtStrings = %#v, want [%q]", rc.TxtStrings, "v=spf1 -all")
+			}
+		default:
+			t.Errorf("unexpected record type %s after round trip", rc.Type)
+		}
+	}
+}